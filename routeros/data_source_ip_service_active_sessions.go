@@ -0,0 +1,146 @@
+package routeros
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// serviceViaName maps an /ip/service name to the value RouterOS reports in
+// /user/active's `via` field for sessions using that service. Most services
+// use their own name; www/www-ssl are reported as http/https.
+var serviceViaName = map[string]string{
+	"www":     "http",
+	"www-ssl": "https",
+}
+
+// DataSourceIpServiceActiveSessions reports, per /ip/service row, how many
+// sessions are currently connected and whether that count has reached the
+// row's max_sessions. It exists so capacity policies (see ResourceIpService's
+// on_capacity block) and operators alike have something to check against
+// instead of having to watch /user/active out of band.
+func DataSourceIpServiceActiveSessions() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceIpServiceActiveSessionsRead,
+
+		Schema: map[string]*schema.Schema{
+			"service": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Restrict the result to these service names. Omit to report on every service.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"services": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Per-service session counts.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Service name.",
+						},
+						"max_sessions": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The service's configured max_sessions, or 0 if unset.",
+						},
+						"active_sessions": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Number of sessions currently connected to this service.",
+						},
+						"at_capacity": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "True when active_sessions has reached max_sessions.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceIpServiceActiveSessionsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client, err := clientFrom(m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	wanted := map[string]bool{}
+	for _, raw := range d.Get("service").([]interface{}) {
+		wanted[raw.(string)] = true
+	}
+
+	ver, err := parseRouterOSVersion(RouterOSVersion)
+	if err != nil {
+		panic(err)
+	}
+
+	filter := map[string]any{}
+	// ROS 7.19 => 463616
+	if ver >= 463616 {
+		filter["dynamic"] = "false"
+	}
+
+	svcRows, err := ReadItemsFiltered(buildReadFilter(filter), "/ip/service", client)
+	if err != nil {
+		ColorizedDebug(ctx, fmt.Sprintf(ErrorMsgGet, err))
+		return diag.FromErr(err)
+	}
+
+	active, err := activeSessionCountsByVia(client)
+	if err != nil {
+		ColorizedDebug(ctx, fmt.Sprintf(ErrorMsgGet, err))
+		return diag.FromErr(err)
+	}
+
+	services := make([]map[string]any, 0, len(*svcRows))
+	for _, row := range *svcRows {
+		name := row["name"]
+		if len(wanted) > 0 && !wanted[name] {
+			continue
+		}
+
+		maxSessions := rowInt(row, "max-sessions", 0)
+		count := active[viaNameFor(name)]
+
+		services = append(services, map[string]any{
+			"name":            name,
+			"max_sessions":    maxSessions,
+			"active_sessions": count,
+			"at_capacity":     maxSessions > 0 && count >= maxSessions,
+		})
+	}
+
+	d.SetId("ip_service_active_sessions")
+
+	return diag.FromErr(d.Set("services", services))
+}
+
+// activeSessionCountsByVia tallies /user/active rows by their `via` field,
+// which is how a single query covers the api/api-ssl session lists as well
+// as the shell-based services.
+func activeSessionCountsByVia(client Client) (map[string]int, error) {
+	rows, err := ReadItemsFiltered(buildReadFilter(map[string]any{}), "/user/active", client)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, row := range *rows {
+		counts[row["via"]]++
+	}
+	return counts, nil
+}
+
+func viaNameFor(serviceName string) string {
+	if via, ok := serviceViaName[serviceName]; ok {
+		return via
+	}
+	return serviceName
+}
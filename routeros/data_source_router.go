@@ -0,0 +1,103 @@
+package routeros
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// DataSourceRouter exposes the connection metadata a named profile resolves
+// to, so downstream modules can key other resources off a router (e.g. in
+// a provider alias or a naming convention) without re-declaring the
+// profile's hostname/transport themselves.
+func DataSourceRouter() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceRouterRead,
+
+		Schema: map[string]*schema.Schema{
+			"profile": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the profile to resolve, as declared in the profiles file.",
+			},
+			"hostname": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Hostname (or host:port) the profile connects to.",
+			},
+			"transport": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Transport the profile uses (rest or api).",
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Username the profile authenticates with.",
+			},
+			"insecure": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the profile skips TLS/host-key verification.",
+			},
+			"profiles_file": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "Profiles file to resolve \"profile\" from when the provider wasn't configured with one " +
+					"(defaults to " + DefaultProfilesFile + "). Ignored when the provider has its own profiles file, " +
+					"which is then always used.",
+			},
+		},
+	}
+}
+
+func dataSourceRouterRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	name := d.Get("profile").(string)
+
+	profile, err := resolveRouterProfile(m, name, d.Get("profiles_file").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(name)
+	if err := d.Set("hostname", profile.Hostname); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("transport", profile.Transport); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("username", profile.Username); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("insecure", profile.Insecure); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// resolveRouterProfile prefers the ProfileStore the provider itself was
+// configured with - so routeros_router sees the exact same profiles file
+// every resource resolves its Client through - and only falls back to
+// loading profilesFileOverride (or DefaultProfilesFile) directly when the
+// provider wasn't configured in profile mode at all.
+func resolveRouterProfile(m interface{}, name, profilesFileOverride string) (Profile, error) {
+	if meta, ok := m.(*ProviderMeta); ok {
+		if store := meta.Store(); store != nil {
+			return store.Profile(name)
+		}
+	}
+
+	profiles, err := loadProfilesFile(profilesFileOverride)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	profile, ok := profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("no profile named %q in profiles file", name)
+	}
+	return profile, nil
+}
@@ -0,0 +1,249 @@
+package routeros
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultProfilesFile is where ProfileStore looks for named router targets
+// when the provider isn't configured with an explicit `profiles_file`.
+const DefaultProfilesFile = "~/.routeros/profiles.yaml"
+
+// Profile is one named router target, as declared in a profiles file. It
+// mirrors the connection attributes the provider block itself accepts, so
+// a profile can stand in for a whole `provider "routeros" { ... }` block.
+type Profile struct {
+	Hostname    string `yaml:"hostname"`
+	Transport   string `yaml:"transport"`
+	Username    string `yaml:"username"`
+	PasswordRef string `yaml:"password_ref"`
+	SSHKeyPath  string `yaml:"ssh_key_path"`
+	HostKey     string `yaml:"host_key"`
+	CACert      string `yaml:"ca_cert"`
+	Insecure    bool   `yaml:"insecure"`
+}
+
+// profilesFile is the on-disk shape of a profiles.yaml: a top-level
+// `profiles` map of profile name to its connection attributes.
+type profilesFile struct {
+	Profiles map[string]Profile `yaml:"profiles"`
+}
+
+// loadProfilesFile reads and parses path (falling back to
+// DefaultProfilesFile when path is empty). It's split out from
+// NewProfileStore so callers that only need profile metadata - like
+// DataSourceRouter - aren't forced to go through a Client-dialing store to
+// get it.
+func loadProfilesFile(path string) (map[string]Profile, error) {
+	if path == "" {
+		path = DefaultProfilesFile
+	}
+
+	expanded, err := expandHome(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolving profiles file path %s: %w", path, err)
+	}
+
+	raw, err := os.ReadFile(expanded)
+	if err != nil {
+		return nil, fmt.Errorf("reading profiles file %s: %w", expanded, err)
+	}
+
+	var pf profilesFile
+	if err := yaml.Unmarshal(raw, &pf); err != nil {
+		return nil, fmt.Errorf("parsing profiles file %s: %w", expanded, err)
+	}
+
+	return pf.Profiles, nil
+}
+
+// clientRef reference-counts a single Client so concurrent Terraform
+// workers addressing the same profile share one underlying connection
+// instead of dialing the router once per resource.
+type clientRef struct {
+	client   Client
+	refCount int
+}
+
+// ProfileStore loads a profiles file once and hands out reference-counted
+// Client instances per profile name. It is safe for concurrent use by
+// multiple Terraform workers: the store's mutex only ever guards its own
+// bookkeeping (the clients and dialing maps), never the network dial
+// itself, so one worker resolving "router-a" never blocks another
+// resolving "router-b" - or a second worker resolving "router-a" at the
+// same time, which instead waits on that dial's result rather than
+// triggering a second one.
+type ProfileStore struct {
+	mu       sync.Mutex
+	profiles map[string]Profile
+	clients  map[string]*clientRef
+	dialing  map[string]*sync.WaitGroup
+	dial     func(Profile) (Client, error)
+}
+
+// NewProfileStore loads path (falling back to DefaultProfilesFile when path
+// is empty) and returns a store ready to resolve Clients by profile name.
+// dial is the provider's existing connection constructor; it's injected so
+// the store itself stays free of transport-specific setup.
+func NewProfileStore(path string, dial func(Profile) (Client, error)) (*ProfileStore, error) {
+	profiles, err := loadProfilesFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProfileStore{
+		profiles: profiles,
+		clients:  map[string]*clientRef{},
+		dialing:  map[string]*sync.WaitGroup{},
+		dial:     dial,
+	}, nil
+}
+
+// Acquire resolves name to a Client, dialing it on first use and reusing
+// the existing connection for every subsequent caller. The returned
+// release func must be called exactly once, when the caller is done with
+// the Client, so the store knows when it's safe to close the connection.
+func (s *ProfileStore) Acquire(name string) (client Client, release func(), err error) {
+	for {
+		s.mu.Lock()
+
+		profile, ok := s.profiles[name]
+		if !ok {
+			s.mu.Unlock()
+			return nil, nil, fmt.Errorf("no profile named %q in profiles file", name)
+		}
+
+		if ref, ok := s.clients[name]; ok {
+			ref.refCount++
+			s.mu.Unlock()
+			return ref.client, s.releaseFunc(name), nil
+		}
+
+		if wg, ok := s.dialing[name]; ok {
+			// Another goroutine is already dialing this profile: wait for
+			// it to finish and retry rather than dialing a second time.
+			s.mu.Unlock()
+			wg.Wait()
+			continue
+		}
+
+		wg := &sync.WaitGroup{}
+		wg.Add(1)
+		s.dialing[name] = wg
+		s.mu.Unlock()
+
+		c, dialErr := s.dialProfile(profile)
+
+		s.mu.Lock()
+		delete(s.dialing, name)
+		wg.Done()
+		if dialErr != nil {
+			s.mu.Unlock()
+			return nil, nil, fmt.Errorf("connecting to profile %q: %w", name, dialErr)
+		}
+		s.clients[name] = &clientRef{client: c, refCount: 1}
+		s.mu.Unlock()
+
+		return c, s.releaseFunc(name), nil
+	}
+}
+
+// dialProfile resolves the profile's password and dials it. It never holds
+// s.mu: ResolvePasswordRef may shell out (an "exec:" password_ref) and dial
+// always does network I/O, so both must run outside the store's lock.
+func (s *ProfileStore) dialProfile(profile Profile) (Client, error) {
+	password, err := ResolvePasswordRef(profile.PasswordRef)
+	if err != nil {
+		return nil, fmt.Errorf("resolving password_ref: %w", err)
+	}
+	profile.PasswordRef = password
+
+	return s.dial(profile)
+}
+
+func (s *ProfileStore) releaseFunc(name string) func() {
+	return func() {
+		s.mu.Lock()
+
+		ref, ok := s.clients[name]
+		if !ok {
+			s.mu.Unlock()
+			return
+		}
+
+		ref.refCount--
+		if ref.refCount > 0 {
+			s.mu.Unlock()
+			return
+		}
+
+		delete(s.clients, name)
+		s.mu.Unlock()
+
+		// Close outside the lock: it may block on network I/O, and by now
+		// the ref is already gone from s.clients so nobody else can see it.
+		if closer, ok := ref.client.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+}
+
+// Profile returns the resolved (but not yet connected) profile metadata for
+// name, for callers like DataSourceRouter that only need to expose
+// connection attributes rather than a live Client.
+func (s *ProfileStore) Profile(name string) (Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	profile, ok := s.profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("no profile named %q in profiles file", name)
+	}
+	return profile, nil
+}
+
+// ResolvePasswordRef turns a profile's password_ref into an actual
+// password. Three forms are supported: a literal value, "env:VAR_NAME" to
+// read an environment variable, and "exec:command" to run an external
+// command (e.g. a keychain lookup) and use its trimmed stdout.
+func ResolvePasswordRef(ref string) (string, error) {
+	switch {
+	case strings.HasPrefix(ref, "env:"):
+		name := strings.TrimPrefix(ref, "env:")
+		val, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return val, nil
+
+	case strings.HasPrefix(ref, "exec:"):
+		command := strings.TrimPrefix(ref, "exec:")
+		out, err := exec.Command("sh", "-c", command).Output()
+		if err != nil {
+			return "", fmt.Errorf("running %q: %w", command, err)
+		}
+		return strings.TrimSpace(string(out)), nil
+
+	default:
+		return ref, nil
+	}
+}
+
+func expandHome(path string) (string, error) {
+	if !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return home + strings.TrimPrefix(path, "~"), nil
+}
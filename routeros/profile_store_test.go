@@ -0,0 +1,137 @@
+package routeros
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestProfileStoreAcquireDedupesConcurrentDials exercises the dialing/
+// sync.WaitGroup dedup path Acquire uses to make sure two callers
+// resolving the same profile at once share a single dial rather than
+// racing to dial it twice, and that the connection is only released once
+// both callers have called their release func.
+func TestProfileStoreAcquireDedupesConcurrentDials(t *testing.T) {
+	var dialCount int32
+	dial := func(Profile) (Client, error) {
+		atomic.AddInt32(&dialCount, 1)
+		time.Sleep(10 * time.Millisecond)
+		return nil, nil
+	}
+
+	store := &ProfileStore{
+		profiles: map[string]Profile{"router-a": {Hostname: "router-a"}},
+		clients:  map[string]*clientRef{},
+		dialing:  map[string]*sync.WaitGroup{},
+		dial:     dial,
+	}
+
+	var wg sync.WaitGroup
+	releases := make([]func(), 2)
+	for i := 0; i < 2; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, release, err := store.Acquire("router-a")
+			if err != nil {
+				t.Errorf("Acquire: %v", err)
+				return
+			}
+			releases[i] = release
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&dialCount); got != 1 {
+		t.Fatalf("dial called %d time(s), want exactly 1", got)
+	}
+
+	ref, ok := store.clients["router-a"]
+	if !ok || ref.refCount != 2 {
+		t.Fatalf("refCount = %+v, want 2", ref)
+	}
+
+	releases[0]()
+	if _, ok := store.clients["router-a"]; !ok {
+		t.Fatal("client must still be held after only one of two callers released")
+	}
+
+	releases[1]()
+	if _, ok := store.clients["router-a"]; ok {
+		t.Fatal("client must be released once every caller has released")
+	}
+}
+
+func TestResolvePasswordRefLiteral(t *testing.T) {
+	got, err := ResolvePasswordRef("hunter2")
+	if err != nil {
+		t.Fatalf("ResolvePasswordRef: %v", err)
+	}
+	if got != "hunter2" {
+		t.Errorf("got %q, want hunter2", got)
+	}
+}
+
+func TestResolvePasswordRefEnv(t *testing.T) {
+	t.Setenv("ROUTEROS_TEST_PASSWORD", "from-env")
+
+	got, err := ResolvePasswordRef("env:ROUTEROS_TEST_PASSWORD")
+	if err != nil {
+		t.Fatalf("ResolvePasswordRef: %v", err)
+	}
+	if got != "from-env" {
+		t.Errorf("got %q, want from-env", got)
+	}
+}
+
+func TestResolvePasswordRefEnvMissing(t *testing.T) {
+	if _, err := ResolvePasswordRef("env:ROUTEROS_TEST_PASSWORD_DOES_NOT_EXIST"); err == nil {
+		t.Fatal("expected an error for an unset environment variable")
+	}
+}
+
+func TestResolvePasswordRefExec(t *testing.T) {
+	got, err := ResolvePasswordRef(fmt.Sprintf("exec:%s", "echo from-exec"))
+	if err != nil {
+		t.Fatalf("ResolvePasswordRef: %v", err)
+	}
+	if got != "from-exec" {
+		t.Errorf("got %q, want from-exec", got)
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("no home directory available: %v", err)
+	}
+
+	got, err := expandHome("~/.routeros/profiles.yaml")
+	if err != nil {
+		t.Fatalf("expandHome: %v", err)
+	}
+	want := home + "/.routeros/profiles.yaml"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestExpandHomeLeavesAbsolutePathAlone(t *testing.T) {
+	got, err := expandHome("/etc/routeros/profiles.yaml")
+	if err != nil {
+		t.Fatalf("expandHome: %v", err)
+	}
+	if got != "/etc/routeros/profiles.yaml" {
+		t.Errorf("got %q, want unchanged path", got)
+	}
+}
+
+func TestLoadProfilesFileMissing(t *testing.T) {
+	if _, err := loadProfilesFile("/nonexistent/profiles.yaml"); err == nil {
+		t.Fatal("expected an error reading a missing profiles file")
+	}
+}
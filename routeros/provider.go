@@ -0,0 +1,135 @@
+package routeros
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Provider returns the routeros provider's schema.Provider. A provider
+// block configures exactly one of two ways to reach a router: the classic
+// attributes below, mirroring Profile field-for-field, for a single
+// hard-coded target; or "profile" (plus an optional "profiles_file") to
+// resolve one of several named targets through a ProfileStore, the same
+// way routeros_router and every resource's clientFrom(m) do.
+func Provider() *schema.Provider {
+	return &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"hostname": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Hostname (or host:port) of the router to connect to. Mutually exclusive with \"profile\".",
+			},
+			"transport": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Transport to connect over (rest or api).",
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Username to authenticate with.",
+			},
+			"password_ref": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "Password to authenticate with, or a reference to one: \"env:VAR_NAME\" or " +
+					"\"exec:command\" (see ResolvePasswordRef).",
+				Sensitive: true,
+			},
+			"ssh_key_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to an SSH private key to authenticate with, for transport = \"ssh\".",
+			},
+			"host_key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Pinned SSH host key fingerprint to verify the router against.",
+			},
+			"ca_cert": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to a CA certificate to verify the router's TLS certificate against.",
+			},
+			"insecure": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Skip TLS/host-key verification.",
+			},
+			"profile": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "Name of a profile to connect as, resolved from \"profiles_file\" (or " +
+					DefaultProfilesFile + " if unset). Mutually exclusive with the classic connection attributes above.",
+				ConflictsWith: []string{"hostname", "transport", "username", "password_ref", "ssh_key_path",
+					"host_key", "ca_cert", "insecure"},
+			},
+			"profiles_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to the profiles file \"profile\" is resolved from.",
+			},
+		},
+
+		ResourcesMap:   Resources(),
+		DataSourcesMap: dataSources(),
+
+		ConfigureContextFunc: configureProvider,
+	}
+}
+
+// dataSources is Provider's equivalent of Resources(): every data source
+// this provider registers, keyed by Terraform type.
+func dataSources() map[string]*schema.Resource {
+	return map[string]*schema.Resource{
+		"routeros_router":                     DataSourceRouter(),
+		"routeros_ip_service_active_sessions": DataSourceIpServiceActiveSessions(),
+	}
+}
+
+// configureProvider builds this provider instance's ProviderMeta: a direct
+// Client from the classic attributes when "profile" is unset, or a
+// ProviderMeta backed by a ProfileStore when it is, so every resource and
+// data source can reach either configuration unchanged through clientFrom.
+func configureProvider(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	profile := d.Get("profile").(string)
+	if profile == "" {
+		classic := classicProfile(d)
+		password, err := ResolvePasswordRef(classic.PasswordRef)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		classic.PasswordRef = password
+
+		client, err := NewClient(classic)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		return NewProviderMeta(client), nil
+	}
+
+	store, err := NewProfileStore(d.Get("profiles_file").(string), NewClient)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	return NewProviderMetaFromProfile(store, profile), nil
+}
+
+// classicProfile packages the provider block's own classic attributes into
+// a Profile, so the single-target configuration dials through exactly the
+// same NewClient(Profile) constructor a named profile does.
+func classicProfile(d *schema.ResourceData) Profile {
+	return Profile{
+		Hostname:    d.Get("hostname").(string),
+		Transport:   d.Get("transport").(string),
+		Username:    d.Get("username").(string),
+		PasswordRef: d.Get("password_ref").(string),
+		SSHKeyPath:  d.Get("ssh_key_path").(string),
+		HostKey:     d.Get("host_key").(string),
+		CACert:      d.Get("ca_cert").(string),
+		Insecure:    d.Get("insecure").(bool),
+	}
+}
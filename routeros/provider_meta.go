@@ -0,0 +1,92 @@
+package routeros
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ProviderMeta is the `meta` value every resource and data source in this
+// provider receives. It normalizes the two ways a provider block can be
+// configured: a single Client built directly from its connection
+// attributes, or a named profile resolved through a ProfileStore. Every
+// resource and data source goes through clientFrom(m) rather than
+// asserting m.(Client) directly, so both configurations work unchanged.
+type ProviderMeta struct {
+	mu sync.Mutex
+
+	client  Client
+	store   *ProfileStore
+	profile string
+	release func()
+}
+
+// NewProviderMeta wraps a Client built directly from the provider block's
+// own connection attributes - the classic, single-target configuration.
+func NewProviderMeta(client Client) *ProviderMeta {
+	return &ProviderMeta{client: client}
+}
+
+// NewProviderMetaFromProfile wraps a ProfileStore plus the name of the
+// profile this provider block (or alias) should resolve as its Client.
+func NewProviderMetaFromProfile(store *ProfileStore, profile string) *ProviderMeta {
+	return &ProviderMeta{store: store, profile: profile}
+}
+
+// Client returns the Client for this provider configuration, resolving and
+// caching it through the ProfileStore on first use when this ProviderMeta
+// was built from a profile rather than a direct Client.
+func (p *ProviderMeta) Client() (Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client != nil {
+		return p.client, nil
+	}
+	if p.store == nil {
+		return nil, fmt.Errorf("provider is not configured with a client or a profile")
+	}
+
+	client, release, err := p.store.Acquire(p.profile)
+	if err != nil {
+		return nil, err
+	}
+
+	p.client = client
+	p.release = release
+	return client, nil
+}
+
+// Store exposes the underlying ProfileStore, for data sources like
+// DataSourceRouter that resolve metadata for profiles other than the one
+// this provider instance itself connects as. Returns nil when the provider
+// was configured with a direct Client instead of a profiles file.
+func (p *ProviderMeta) Store() *ProfileStore {
+	return p.store
+}
+
+// Close releases this ProviderMeta's reference on the ProfileStore, if it
+// acquired one. Safe to call even when Client was never called.
+func (p *ProviderMeta) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.release != nil {
+		p.release()
+		p.release = nil
+	}
+}
+
+// clientFrom resolves a Client from a resource or data source's meta
+// value, accepting either a bare Client (for any test or caller that still
+// configures the provider that way) or the *ProviderMeta every real
+// resource/data source in this provider is configured with.
+func clientFrom(m interface{}) (Client, error) {
+	switch v := m.(type) {
+	case Client:
+		return v, nil
+	case *ProviderMeta:
+		return v.Client()
+	default:
+		return nil, fmt.Errorf("unexpected provider meta type %T", m)
+	}
+}
@@ -72,6 +72,31 @@ func ResourceIpService() *schema.Resource {
 			Computed:    true,
 			Description: "Service name.",
 		},
+		"on_capacity": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Description: "Capacity policy evaluated against routeros_ip_service_active_sessions when max_sessions " +
+				"is set, so the provider can react instead of the limit silently being hit.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"action": {
+						Type:     schema.TypeString,
+						Required: true,
+						Description: "What to do when active sessions reach max_sessions: \"log\" only warns, " +
+							"\"disable\" disables the service, \"raise_limit\" increases max_sessions by raise_by, " +
+							"\"fail\" stops the apply instead of writing the change.",
+						ValidateFunc: validation.StringInSlice([]string{"log", "disable", "raise_limit", "fail"}, false),
+					},
+					"raise_by": {
+						Type:        schema.TypeInt,
+						Optional:    true,
+						Default:     0,
+						Description: "Amount to add to max_sessions when action is \"raise_limit\".",
+					},
+				},
+			},
+		},
 		"numbers": {
 			Type:     schema.TypeString,
 			Required: true,
@@ -101,6 +126,11 @@ func ResourceIpService() *schema.Resource {
 	}
 
 	resRead := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		client, err := clientFrom(m)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
 		path := resSchema[MetaResourcePath].Default.(string)
 		filter := map[string]any{"name": d.Get("numbers")}
 
@@ -114,7 +144,7 @@ func ResourceIpService() *schema.Resource {
 			filter["dynamic"] = "false"
 		}
 
-		res, err := ReadItemsFiltered(buildReadFilter(filter), path, m.(Client))
+		res, err := ReadItemsFiltered(buildReadFilter(filter), path, client)
 		if err != nil {
 			ColorizedDebug(ctx, fmt.Sprintf(ErrorMsgGet, err))
 			return diag.FromErr(err)
@@ -133,19 +163,27 @@ func ResourceIpService() *schema.Resource {
 	}
 
 	resCreateUpdate := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		client, err := clientFrom(m)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
 		item, metadata := TerraformResourceDataToMikrotik(resSchema, d)
 
 		d.SetId(d.Get("numbers").(string))
 
+		if diags := enforceCapacityPolicy(ctx, d, client, item); diags.HasError() {
+			return diags
+		}
+
 		var resUrl string
-		if m.(Client).GetTransport() == TransportREST {
+		if client.GetTransport() == TransportREST {
 			// https://router/rest/system/identity/set
 			// https://router/rest/caps-man/manager/set
 			resUrl = "/set"
 		}
 
-		err := m.(Client).SendRequest(crudPost, &URL{Path: metadata.Path + resUrl}, item, nil)
-		if err != nil {
+		if err := client.SendRequest(crudPost, &URL{Path: metadata.Path + resUrl}, item, nil); err != nil {
 			return diag.FromErr(err)
 		}
 
@@ -157,6 +195,7 @@ func ResourceIpService() *schema.Resource {
 		ReadContext:   resRead,
 		UpdateContext: resCreateUpdate,
 		DeleteContext: DefaultSystemDelete(resSchema),
+		CustomizeDiff: customizeOnCapacityDiff,
 
 		Importer: &schema.ResourceImporter{
 			StateContext: ImportStateCustomContext(resSchema),
@@ -165,3 +204,119 @@ func ResourceIpService() *schema.Resource {
 		Schema: resSchema,
 	}
 }
+
+// customizeOnCapacityDiff makes on_capacity = { action = "fail" } observable
+// at plan time instead of only surfacing once apply is already underway: it
+// re-reads the same active-session counts enforceCapacityPolicy checks at
+// apply and fails the plan outright if the service is already at capacity.
+// The other actions (log/disable/raise_limit) only decide what to write, so
+// they stay apply-time work in enforceCapacityPolicy.
+func customizeOnCapacityDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	policies := d.Get("on_capacity").([]interface{})
+	if len(policies) == 0 {
+		return nil
+	}
+
+	policy := policies[0].(map[string]interface{})
+	if policy["action"].(string) != "fail" {
+		return nil
+	}
+
+	maxSessions := d.Get("max_sessions").(int)
+	if maxSessions <= 0 {
+		return nil
+	}
+
+	client, err := clientFrom(m)
+	if err != nil {
+		return err
+	}
+
+	active, err := activeSessionCountsByVia(client)
+	if err != nil {
+		return fmt.Errorf("checking active sessions for capacity policy: %w", err)
+	}
+
+	numbers := d.Get("numbers").(string)
+	count := active[viaNameFor(numbers)]
+	if count < maxSessions {
+		return nil
+	}
+
+	return fmt.Errorf(capacityBreachMsg, numbers, count, maxSessions)
+}
+
+// enforceCapacityPolicy checks the declared on_capacity block (if any)
+// against the current session count for this service, reported the same
+// way DataSourceIpServiceActiveSessions computes it, and mutates item in
+// place when the policy calls for raising or disabling the service.
+func enforceCapacityPolicy(ctx context.Context, d *schema.ResourceData, client Client, item map[string]string) diag.Diagnostics {
+	policies := d.Get("on_capacity").([]interface{})
+	if len(policies) == 0 {
+		return nil
+	}
+
+	maxSessions := d.Get("max_sessions").(int)
+	if maxSessions <= 0 {
+		return nil
+	}
+
+	policy := policies[0].(map[string]interface{})
+	action := policy["action"].(string)
+
+	active, err := activeSessionCountsByVia(client)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("checking active sessions for capacity policy: %w", err))
+	}
+
+	count := active[viaNameFor(d.Get("numbers").(string))]
+
+	return applyCapacityAction(ctx, capacityPolicyInput{
+		Service:     d.Get("numbers").(string),
+		Action:      action,
+		RaiseBy:     policy["raise_by"].(int),
+		Count:       count,
+		MaxSessions: maxSessions,
+	}, item)
+}
+
+// capacityPolicyInput is everything applyCapacityAction needs to decide what
+// to do about a capacity breach, pulled out of enforceCapacityPolicy's
+// ResourceData/Client plumbing so the decision itself can be unit tested.
+type capacityPolicyInput struct {
+	Service     string
+	Action      string
+	RaiseBy     int
+	Count       int
+	MaxSessions int
+}
+
+// applyCapacityAction decides what on_capacity does once a service has hit
+// its max_sessions: log and continue, disable the service, raise the limit,
+// or fail the apply outright. It mutates item in place for the actions that
+// change what gets written to the router.
+func applyCapacityAction(ctx context.Context, in capacityPolicyInput, item map[string]string) diag.Diagnostics {
+	if in.Count < in.MaxSessions {
+		return nil
+	}
+
+	switch in.Action {
+	case "log":
+		ColorizedDebug(ctx, fmt.Sprintf("service %s is at capacity (%d/%d active sessions)",
+			in.Service, in.Count, in.MaxSessions))
+	case "disable":
+		item[KeyDisabled] = "true"
+	case "raise_limit":
+		item["max-sessions"] = fmt.Sprintf("%d", in.MaxSessions+in.RaiseBy)
+	case "fail":
+		return diag.Errorf(capacityBreachMsg, in.Service, in.Count, in.MaxSessions)
+	}
+
+	return nil
+}
+
+// capacityBreachMsg is the message on_capacity.action = "fail" reports,
+// whether it's caught at plan time by customizeOnCapacityDiff or, as a
+// defense-in-depth re-check against live session counts, at apply time by
+// applyCapacityAction.
+const capacityBreachMsg = "service %s is at capacity (%d/%d active sessions) and on_capacity.action is \"fail\""
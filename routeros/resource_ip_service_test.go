@@ -0,0 +1,88 @@
+package routeros
+
+import (
+	"context"
+	"testing"
+)
+
+func TestApplyCapacityActionBelowCapacityIsNoop(t *testing.T) {
+	item := map[string]string{}
+	diags := applyCapacityAction(context.Background(), capacityPolicyInput{
+		Service: "api", Action: "fail", Count: 1, MaxSessions: 5,
+	}, item)
+
+	if diags.HasError() {
+		t.Fatalf("unexpected error below capacity: %v", diags)
+	}
+	if len(item) != 0 {
+		t.Errorf("item must be untouched below capacity, got %v", item)
+	}
+}
+
+func TestApplyCapacityActionLogLeavesItemUnchanged(t *testing.T) {
+	item := map[string]string{}
+	diags := applyCapacityAction(context.Background(), capacityPolicyInput{
+		Service: "api", Action: "log", Count: 5, MaxSessions: 5,
+	}, item)
+
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if len(item) != 0 {
+		t.Errorf("\"log\" must not change item, got %v", item)
+	}
+}
+
+func TestApplyCapacityActionDisable(t *testing.T) {
+	item := map[string]string{}
+	diags := applyCapacityAction(context.Background(), capacityPolicyInput{
+		Service: "api", Action: "disable", Count: 5, MaxSessions: 5,
+	}, item)
+
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if item[KeyDisabled] != "true" {
+		t.Errorf("item[%s] = %q, want true", KeyDisabled, item[KeyDisabled])
+	}
+}
+
+func TestApplyCapacityActionRaiseLimit(t *testing.T) {
+	item := map[string]string{}
+	diags := applyCapacityAction(context.Background(), capacityPolicyInput{
+		Service: "api", Action: "raise_limit", RaiseBy: 10, Count: 5, MaxSessions: 5,
+	}, item)
+
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if item["max-sessions"] != "15" {
+		t.Errorf("item[max-sessions] = %q, want 15", item["max-sessions"])
+	}
+}
+
+func TestApplyCapacityActionFailStopsTheApply(t *testing.T) {
+	item := map[string]string{}
+	diags := applyCapacityAction(context.Background(), capacityPolicyInput{
+		Service: "api", Action: "fail", Count: 5, MaxSessions: 5,
+	}, item)
+
+	if !diags.HasError() {
+		t.Fatal("expected \"fail\" to stop the apply with an error")
+	}
+	if len(item) != 0 {
+		t.Errorf("\"fail\" must not write any change to item, got %v", item)
+	}
+}
+
+func TestViaNameForMapsWebServices(t *testing.T) {
+	if got := viaNameFor("www"); got != "http" {
+		t.Errorf("viaNameFor(www) = %q, want http", got)
+	}
+	if got := viaNameFor("www-ssl"); got != "https" {
+		t.Errorf("viaNameFor(www-ssl) = %q, want https", got)
+	}
+	if got := viaNameFor("ssh"); got != "ssh" {
+		t.Errorf("viaNameFor(ssh) = %q, want ssh unchanged", got)
+	}
+}
@@ -0,0 +1,393 @@
+package routeros
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// ipServicesSingletonId is the fixed Terraform ID for ResourceIpServices:
+// unlike ResourceIpService, which addresses one `/ip/service` row via
+// `numbers`, this resource owns the whole table, so there is no per-row
+// value to key the ID on.
+const ipServicesSingletonId = "ip_services"
+
+// ipServiceFactoryDefault is what RouterOS ships with out of the box for a
+// given service name. ResourceIpServices falls back to it for any field a
+// `service` block omits, for the port of any service the operator's config
+// doesn't declare (which is also disabled), and for every field on destroy.
+type ipServiceFactoryDefault struct {
+	Port     int
+	Disabled bool
+}
+
+var ipServiceFactoryDefaults = map[string]ipServiceFactoryDefault{
+	"api":     {Port: 8728, Disabled: false},
+	"api-ssl": {Port: 8729, Disabled: true},
+	"ftp":     {Port: 21, Disabled: false},
+	"ssh":     {Port: 22, Disabled: false},
+	"telnet":  {Port: 23, Disabled: false},
+	"winbox":  {Port: 8291, Disabled: false},
+	"www":     {Port: 80, Disabled: false},
+	"www-ssl": {Port: 443, Disabled: true},
+}
+
+// ipServiceNames is every service RouterOS exposes under /ip/service, in a
+// stable order so reads and diffs are deterministic.
+var ipServiceNames = func() []string {
+	names := make([]string, 0, len(ipServiceFactoryDefaults))
+	for name := range ipServiceFactoryDefaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}()
+
+// https://help.mikrotik.com/docs/display/ROS/Services
+//
+// ResourceIpServices manages the entire /ip/service table as a single
+// resource: a `service` block per service the operator cares about, with
+// every other service disabled and its port, address and certificate reset
+// to RouterOS factory defaults. This lets
+// operators declare "these are the only services enabled on this router"
+// in one place, which the per-row ResourceIpService can't express because
+// its rows can't be removed.
+func ResourceIpServices() *schema.Resource {
+	resSchema := map[string]*schema.Schema{
+		MetaResourcePath: PropResourcePath("/ip/service"),
+
+		"service": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "One block per service to keep under management. Every service without a block is disabled.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:             schema.TypeString,
+						Required:         true,
+						Description:      "Service name.",
+						ValidateDiagFunc: ValidationMultiValInSlice(ipServiceNames, false, false),
+					},
+					"port": {
+						Type:         schema.TypeInt,
+						Optional:     true,
+						Computed:     true,
+						Description:  "The port particular service listens on.",
+						ValidateFunc: validation.IntBetween(1, 65535),
+					},
+					"address": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Computed:    true,
+						Description: "List of IP/IPv6 prefixes from which the service is accessible.",
+					},
+					"certificate": {
+						Type:     schema.TypeString,
+						Optional: true,
+						Computed: true,
+						Description: "The name of the certificate used by a particular service. Applicable only for " +
+							"services that depend on certificates ( www-ssl, api-ssl ).",
+					},
+					KeyDisabled: {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Computed:    true,
+						Description: "Whether the service is disabled.",
+					},
+				},
+			},
+		},
+	}
+
+	resRead := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		client, err := clientFrom(m)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		rows, err := readNonDynamicServices(resSchema, client)
+		if err != nil {
+			ColorizedDebug(ctx, fmt.Sprintf(ErrorMsgGet, err))
+			return diag.FromErr(err)
+		}
+
+		services := make([]map[string]any, 0, len(rows))
+		for _, name := range ipServiceNames {
+			row, ok := rows[name]
+			if !ok {
+				continue
+			}
+
+			def := ipServiceFactoryDefaults[name]
+			services = append(services, map[string]any{
+				"name":        name,
+				"port":        rowInt(row, "port", def.Port),
+				"address":     rowString(row, "address", ""),
+				"certificate": rowString(row, "certificate", ""),
+				KeyDisabled:   rowBool(row, KeyDisabled, def.Disabled),
+			})
+		}
+
+		d.SetId(ipServicesSingletonId)
+
+		return diag.FromErr(d.Set("service", services))
+	}
+
+	resCreateUpdate := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		client, err := clientFrom(m)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		rows, err := readNonDynamicServices(resSchema, client)
+		if err != nil {
+			ColorizedDebug(ctx, fmt.Sprintf(ErrorMsgGet, err))
+			return diag.FromErr(err)
+		}
+
+		declared := declaredServices(d)
+
+		for name, row := range rows {
+			desired := desiredServiceState(name, declared[name])
+			if err := applyServiceDiff(client, row, name, desired); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		return resRead(ctx, d, m)
+	}
+
+	return &schema.Resource{
+		CreateContext: resCreateUpdate,
+		ReadContext:   resRead,
+		UpdateContext: resCreateUpdate,
+		DeleteContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			client, err := clientFrom(m)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			rows, err := readNonDynamicServices(resSchema, client)
+			if err != nil {
+				ColorizedDebug(ctx, fmt.Sprintf(ErrorMsgGet, err))
+				return diag.FromErr(err)
+			}
+
+			for name, row := range rows {
+				if err := applyServiceDiff(client, row, name, factoryResetServiceState(name)); err != nil {
+					return diag.FromErr(err)
+				}
+			}
+
+			d.SetId("")
+			return nil
+		},
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: resSchema,
+	}
+}
+
+// readNonDynamicServices returns every non-dynamic /ip/service row, keyed by
+// service name, respecting the same ROS >= 7.19 `dynamic=false` filter
+// ResourceIpService's resRead already applies.
+func readNonDynamicServices(resSchema map[string]*schema.Schema, client Client) (map[string]map[string]string, error) {
+	path := resSchema[MetaResourcePath].Default.(string)
+
+	ver, err := parseRouterOSVersion(RouterOSVersion)
+	if err != nil {
+		panic(err)
+	}
+
+	filter := map[string]any{}
+	// ROS 7.19 => 463616
+	if ver >= 463616 {
+		filter["dynamic"] = "false"
+	}
+
+	res, err := ReadItemsFiltered(buildReadFilter(filter), path, client)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make(map[string]map[string]string, len(*res))
+	for _, item := range *res {
+		rows[item["name"]] = item
+	}
+
+	return rows, nil
+}
+
+// declaredService is one operator-declared `service` block, with Disabled
+// only meaningful when DisabledSet is true - Terraform always populates a
+// nested block's bool fields with a concrete value (false when omitted),
+// so the zero value can't be used as an "unset" sentinel the way it can
+// for port (no valid value is 0) or address/certificate (their factory
+// default is already "").
+type declaredService struct {
+	Port        int
+	Address     string
+	Certificate string
+	Disabled    bool
+	DisabledSet bool
+}
+
+// declaredServices reads the operator's `service` blocks, keyed by name,
+// consulting GetRawConfig to tell an explicit `disabled = false` apart from
+// the field being left out of the block entirely.
+func declaredServices(d *schema.ResourceData) map[string]*declaredService {
+	declared := map[string]*declaredService{}
+
+	blocks := d.Get("service").([]interface{})
+
+	var rawBlocks []cty.Value
+	if rawList := d.GetRawConfig().GetAttr("service"); !rawList.IsNull() && rawList.IsKnown() {
+		for it := rawList.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			rawBlocks = append(rawBlocks, v)
+		}
+	}
+
+	for i, raw := range blocks {
+		block := raw.(map[string]interface{})
+		name := block["name"].(string)
+
+		ds := &declaredService{
+			Port:        block["port"].(int),
+			Address:     block["address"].(string),
+			Certificate: block["certificate"].(string),
+			Disabled:    block[KeyDisabled].(bool),
+		}
+
+		if i < len(rawBlocks) {
+			if v := rawBlocks[i].GetAttr(KeyDisabled); !v.IsNull() {
+				ds.DisabledSet = true
+			}
+		}
+
+		declared[name] = ds
+	}
+
+	return declared
+}
+
+// desiredServiceState merges the operator's declared block (if any) over
+// the RouterOS factory default for that service name, so every field the
+// block omits still resolves to a concrete value rather than "no change".
+func desiredServiceState(name string, declared *declaredService) map[string]any {
+	def := ipServiceFactoryDefaults[name]
+	desired := map[string]any{
+		"port":        def.Port,
+		"address":     "",
+		"certificate": "",
+		KeyDisabled:   def.Disabled,
+	}
+	if declared == nil {
+		// Not declared by the operator: lock it down so the resource can
+		// assert "these are the only services enabled", regardless of
+		// whether RouterOS ships that service enabled or disabled.
+		desired[KeyDisabled] = true
+		return desired
+	}
+
+	if declared.Port != 0 {
+		desired["port"] = declared.Port
+	}
+	if declared.Address != "" {
+		desired["address"] = declared.Address
+	}
+	if declared.Certificate != "" {
+		desired["certificate"] = declared.Certificate
+	}
+	if declared.DisabledSet {
+		desired[KeyDisabled] = declared.Disabled
+	}
+
+	return desired
+}
+
+// factoryResetServiceState is what DeleteContext drives every service back
+// to: the RouterOS factory default for every field, including Disabled. This
+// is deliberately distinct from desiredServiceState(name, nil), which locks
+// an undeclared service down during create/update - destroy means "restore
+// factory defaults", not "lock down", so ssh/winbox/www/etc. must come back
+// enabled rather than end up disabled.
+func factoryResetServiceState(name string) map[string]any {
+	def := ipServiceFactoryDefaults[name]
+	return map[string]any{
+		"port":        def.Port,
+		"address":     "",
+		"certificate": "",
+		KeyDisabled:   def.Disabled,
+	}
+}
+
+// applyServiceDiff issues a single /set for row if any of desired's fields
+// differ from what's currently on the router, mirroring ResourceIpService's
+// single-row update but scoped to one row out of many.
+func applyServiceDiff(client Client, row map[string]string, name string, desired map[string]any) error {
+	def := ipServiceFactoryDefaults[name]
+
+	changed := map[string]any{}
+	if rowInt(row, "port", def.Port) != desired["port"].(int) {
+		changed["port"] = desired["port"]
+	}
+	if rowString(row, "address", "") != desired["address"].(string) {
+		changed["address"] = desired["address"]
+	}
+	if rowString(row, "certificate", "") != desired["certificate"].(string) {
+		changed["certificate"] = desired["certificate"]
+	}
+	if rowBool(row, KeyDisabled, def.Disabled) != desired[KeyDisabled].(bool) {
+		changed[KeyDisabled] = desired[KeyDisabled]
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	changed[".id"] = row[Id]
+
+	var resUrl string
+	if client.GetTransport() == TransportREST {
+		resUrl = "/set"
+	}
+
+	return client.SendRequest(crudPost, &URL{Path: "/ip/service" + resUrl}, changed, nil)
+}
+
+func rowString(row map[string]string, key, def string) string {
+	if v, ok := row[key]; ok {
+		return v
+	}
+	return def
+}
+
+func rowInt(row map[string]string, key string, def int) int {
+	v, ok := row[key]
+	if !ok || v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+func rowBool(row map[string]string, key string, def bool) bool {
+	v, ok := row[key]
+	if !ok || v == "" {
+		return def
+	}
+	return v == "true"
+}
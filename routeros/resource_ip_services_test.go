@@ -0,0 +1,129 @@
+package routeros
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDesiredServiceStateNotDeclaredResetsToFactory(t *testing.T) {
+	desired := desiredServiceState("www-ssl", nil)
+
+	if desired[KeyDisabled].(bool) != true {
+		t.Errorf("an undeclared service must be reset disabled, got %v", desired[KeyDisabled])
+	}
+	if desired["port"].(int) != ipServiceFactoryDefaults["www-ssl"].Port {
+		t.Errorf("port = %v, want factory port %d", desired["port"], ipServiceFactoryDefaults["www-ssl"].Port)
+	}
+}
+
+func TestDesiredServiceStateOmittedDisabledFallsBackToFactoryDefault(t *testing.T) {
+	// A declared block that never sets "disabled" must fall back to this
+	// service's factory default, not silently resolve to false.
+	declared := &declaredService{Port: 443, DisabledSet: false}
+
+	desired := desiredServiceState("www-ssl", declared)
+	if desired[KeyDisabled].(bool) != ipServiceFactoryDefaults["www-ssl"].Disabled {
+		t.Errorf("disabled = %v, want factory default %v", desired[KeyDisabled], ipServiceFactoryDefaults["www-ssl"].Disabled)
+	}
+}
+
+func TestDesiredServiceStateExplicitDisabledOverrides(t *testing.T) {
+	declared := &declaredService{Disabled: false, DisabledSet: true}
+
+	desired := desiredServiceState("www-ssl", declared)
+	if desired[KeyDisabled].(bool) != false {
+		t.Errorf("explicit disabled=false must override the factory default, got %v", desired[KeyDisabled])
+	}
+}
+
+func TestDesiredServiceStateDeclaredFieldsOverrideDefaults(t *testing.T) {
+	declared := &declaredService{Port: 8443, Address: "10.0.0.0/8", Certificate: "my-cert", DisabledSet: true, Disabled: true}
+
+	desired := desiredServiceState("www-ssl", declared)
+	if desired["port"].(int) != 8443 {
+		t.Errorf("port = %v, want 8443", desired["port"])
+	}
+	if desired["address"].(string) != "10.0.0.0/8" {
+		t.Errorf("address = %v, want 10.0.0.0/8", desired["address"])
+	}
+	if desired["certificate"].(string) != "my-cert" {
+		t.Errorf("certificate = %v, want my-cert", desired["certificate"])
+	}
+}
+
+func TestFactoryResetServiceStateRestoresEnabledDefaults(t *testing.T) {
+	// Destroy must restore factory defaults, not lock services down: ssh,
+	// winbox, www, ftp, telnet and api all ship enabled out of the box.
+	for _, name := range []string{"ssh", "winbox", "www", "ftp", "telnet", "api"} {
+		desired := factoryResetServiceState(name)
+		if desired[KeyDisabled].(bool) != false {
+			t.Errorf("%s: disabled = %v, want factory default false", name, desired[KeyDisabled])
+		}
+	}
+}
+
+func TestFactoryResetServiceStatePreservesDisabledDefaults(t *testing.T) {
+	for _, name := range []string{"www-ssl", "api-ssl"} {
+		desired := factoryResetServiceState(name)
+		if desired[KeyDisabled].(bool) != true {
+			t.Errorf("%s: disabled = %v, want factory default true", name, desired[KeyDisabled])
+		}
+	}
+}
+
+func TestDeclaredServicesDistinguishesOmittedFromExplicitDisabled(t *testing.T) {
+	resSchema := ResourceIpServices().Schema
+
+	omitted := schema.TestResourceDataRaw(t, resSchema, map[string]interface{}{
+		"service": []interface{}{
+			map[string]interface{}{"name": "www-ssl", "port": 443},
+		},
+	})
+	ds, ok := declaredServices(omitted)["www-ssl"]
+	if !ok {
+		t.Fatal("expected a declared service for www-ssl")
+	}
+	if ds.DisabledSet {
+		t.Errorf("disabled left out of the config must not be marked DisabledSet, got %+v", ds)
+	}
+
+	explicit := schema.TestResourceDataRaw(t, resSchema, map[string]interface{}{
+		"service": []interface{}{
+			map[string]interface{}{"name": "www-ssl", "port": 443, KeyDisabled: false},
+		},
+	})
+	ds, ok = declaredServices(explicit)["www-ssl"]
+	if !ok {
+		t.Fatal("expected a declared service for www-ssl")
+	}
+	if !ds.DisabledSet {
+		t.Errorf("explicit disabled = false in the config must be marked DisabledSet, got %+v", ds)
+	}
+	if ds.Disabled != false {
+		t.Errorf("Disabled = %v, want false", ds.Disabled)
+	}
+}
+
+func TestRowHelpers(t *testing.T) {
+	row := map[string]string{"port": "443", "disabled": "true", "address": "10.0.0.0/8"}
+
+	if got := rowInt(row, "port", 0); got != 443 {
+		t.Errorf("rowInt(port) = %d, want 443", got)
+	}
+	if got := rowInt(row, "missing", 99); got != 99 {
+		t.Errorf("rowInt(missing) = %d, want default 99", got)
+	}
+	if got := rowBool(row, "disabled", false); got != true {
+		t.Errorf("rowBool(disabled) = %v, want true", got)
+	}
+	if got := rowBool(row, "missing", true); got != true {
+		t.Errorf("rowBool(missing) = %v, want default true", got)
+	}
+	if got := rowString(row, "address", ""); got != "10.0.0.0/8" {
+		t.Errorf("rowString(address) = %q, want 10.0.0.0/8", got)
+	}
+	if got := rowString(row, "missing", "fallback"); got != "fallback" {
+		t.Errorf("rowString(missing) = %q, want default fallback", got)
+	}
+}
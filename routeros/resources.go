@@ -0,0 +1,25 @@
+package routeros
+
+import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+// resourceFactories is every resource this provider registers, keyed by its
+// Terraform type. Provider() builds its ResourcesMap from this list via
+// Resources(), and tooling outside this package (tools/importer) walks the
+// same resource schemas instead of hand-maintaining its own copy of this
+// list.
+var resourceFactories = map[string]func() *schema.Resource{
+	"routeros_ip_service":  ResourceIpService,
+	"routeros_ip_services": ResourceIpServices,
+}
+
+// Resources instantiates every resource this provider registers, keyed by
+// Terraform type. Exported so external tooling can discover resources and
+// their MetaResourcePath/MetaId metadata without this package needing to
+// know the tooling exists.
+func Resources() map[string]*schema.Resource {
+	out := make(map[string]*schema.Resource, len(resourceFactories))
+	for tfType, factory := range resourceFactories {
+		out[tfType] = factory()
+	}
+	return out
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// stanza is one `/export terse` block: the path it was printed under
+// (e.g. "/ip/service"), the `[ find ... ]` selector fields RouterOS used to
+// identify the row, and the "key=value" pairs assigned to it, with
+// RouterOS's `\` line continuations already joined.
+type stanza struct {
+	Path     string
+	Selector map[string]string
+	Values   map[string]string
+}
+
+// parseExport turns the raw output of `/export terse` into one stanza per
+// `set`/`add` line. RouterOS terse export looks like:
+//
+//	/ip service
+//	set [ find default-name=telnet ] disabled=yes
+//	set [ find default-name=www-ssl ] certificate=https-cert port=443
+//
+// Each `set`/`add` line inherits the path announced by the most recent
+// bare `/path` line above it.
+func parseExport(raw string) ([]stanza, error) {
+	var stanzas []stanza
+	currentPath := ""
+
+	for _, line := range joinContinuations(raw) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "/") {
+			currentPath = normalizePath(line)
+			continue
+		}
+
+		if !strings.HasPrefix(line, "set ") && !strings.HasPrefix(line, "add ") {
+			continue
+		}
+
+		if currentPath == "" {
+			return nil, fmt.Errorf("export line %q has no preceding /path", line)
+		}
+
+		selector, values := parseKeyValues(line)
+		stanzas = append(stanzas, stanza{
+			Path:     currentPath,
+			Selector: selector,
+			Values:   values,
+		})
+	}
+
+	return stanzas, nil
+}
+
+// joinContinuations collapses RouterOS's trailing-backslash line wrapping
+// back into single logical lines before field splitting.
+func joinContinuations(raw string) []string {
+	rawLines := strings.Split(raw, "\n")
+	var lines []string
+	var pending strings.Builder
+
+	for _, l := range rawLines {
+		l = strings.TrimRight(l, "\r")
+		trimmed := strings.TrimSuffix(l, "\\")
+		pending.WriteString(strings.TrimSpace(trimmed))
+		if trimmed == l {
+			lines = append(lines, pending.String())
+			pending.Reset()
+		} else {
+			pending.WriteString(" ")
+		}
+	}
+	if pending.Len() > 0 {
+		lines = append(lines, pending.String())
+	}
+
+	return lines
+}
+
+// normalizePath rewrites the space-separated path RouterOS prints
+// ("/ip service") into the slash-separated form used by MetaResourcePath
+// ("/ip/service").
+func normalizePath(line string) string {
+	fields := strings.Fields(line)
+	return "/" + strings.Join(fields, "/")
+}
+
+// tokenizeExportLine splits a `set`/`add` line on whitespace the way
+// RouterOS itself would re-parse it, treating a double-quoted span (which
+// may contain spaces, e.g. `comment="Uplink to ISP"`) as a single token
+// instead of splitting it apart.
+func tokenizeExportLine(line string) []string {
+	var tokens []string
+	var cur strings.Builder
+	inQuotes := false
+
+	flush := func() {
+		if cur.Len() > 0 {
+			tokens = append(tokens, cur.String())
+			cur.Reset()
+		}
+	}
+
+	for _, r := range line {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			cur.WriteRune(r)
+		case r == ' ' && !inQuotes:
+			flush()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// parseKeyValues extracts the `[ find ... ]` selector fields (the
+// attributes RouterOS used to identify the row, and what the importer must
+// use to resolve `.id` via GetResourceId) separately from the `key=value`
+// and `key="quoted value"` pairs actually being assigned by the
+// `set`/`add` line.
+func parseKeyValues(line string) (selector map[string]string, values map[string]string) {
+	selector = make(map[string]string)
+	values = make(map[string]string)
+
+	inSelector := false
+	for _, tok := range tokenizeExportLine(line) {
+		switch {
+		case tok == "[" || tok == "find":
+			inSelector = true
+			continue
+		case tok == "]":
+			inSelector = false
+			continue
+		}
+
+		eq := strings.Index(tok, "=")
+		if eq < 0 {
+			continue
+		}
+
+		key := tok[:eq]
+		val := strings.Trim(tok[eq+1:], `"`)
+
+		if inSelector {
+			selector[key] = val
+		} else {
+			values[key] = val
+		}
+	}
+
+	return selector, values
+}
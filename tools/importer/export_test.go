@@ -0,0 +1,64 @@
+package main
+
+import "testing"
+
+func TestParseExportSelectorAndValues(t *testing.T) {
+	raw := "/ip service\n" +
+		"set [ find default-name=telnet ] disabled=yes\n" +
+		"set [ find default-name=www-ssl ] certificate=https-cert port=443 comment=\"Uplink to ISP\"\n"
+
+	stanzas, err := parseExport(raw)
+	if err != nil {
+		t.Fatalf("parseExport: %v", err)
+	}
+	if len(stanzas) != 2 {
+		t.Fatalf("expected 2 stanzas, got %d", len(stanzas))
+	}
+
+	first := stanzas[0]
+	if first.Path != "/ip/service" {
+		t.Errorf("Path = %q, want /ip/service", first.Path)
+	}
+	if first.Selector["default-name"] != "telnet" {
+		t.Errorf("Selector[default-name] = %q, want telnet", first.Selector["default-name"])
+	}
+	if first.Values["disabled"] != "yes" {
+		t.Errorf("Values[disabled] = %q, want yes", first.Values["disabled"])
+	}
+	if _, ok := first.Values["default-name"]; ok {
+		t.Errorf("selector field default-name leaked into Values")
+	}
+
+	second := stanzas[1]
+	if second.Values["comment"] != "Uplink to ISP" {
+		t.Errorf("Values[comment] = %q, want %q (quoted value with a space must not be truncated)",
+			second.Values["comment"], "Uplink to ISP")
+	}
+	if second.Values["port"] != "443" {
+		t.Errorf("Values[port] = %q, want 443", second.Values["port"])
+	}
+}
+
+func TestParseExportLineContinuation(t *testing.T) {
+	raw := "/ip service\n" +
+		"set [ find default-name=www-ssl ] \\\n" +
+		"    certificate=https-cert port=443\n"
+
+	stanzas, err := parseExport(raw)
+	if err != nil {
+		t.Fatalf("parseExport: %v", err)
+	}
+	if len(stanzas) != 1 {
+		t.Fatalf("expected 1 stanza, got %d", len(stanzas))
+	}
+	if stanzas[0].Values["certificate"] != "https-cert" {
+		t.Errorf("Values[certificate] = %q, want https-cert", stanzas[0].Values["certificate"])
+	}
+}
+
+func TestParseExportMissingPath(t *testing.T) {
+	_, err := parseExport("set [ find default-name=telnet ] disabled=yes\n")
+	if err == nil {
+		t.Fatal("expected an error for a set line with no preceding /path")
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/davidwuest/terraform-provider-routeros/routeros"
+)
+
+// plannedResource is one router row resolved against a registered schema,
+// ready to be rendered as an import block plus matching HCL.
+type plannedResource struct {
+	TerraformType string
+	ResourceName  string
+	ID            string
+	Attributes    map[string]string
+}
+
+// planResources matches each parsed export stanza to a registered resource
+// by path, resolves its `.id` over the SSH connection the same way
+// GetResourceId already does for single-row lookups, and maps the
+// remaining non-default attributes through the reverse of
+// MikrotikResourceDataToTerraform. A row whose `.id` doesn't resolve (a
+// transient SSH error, most likely) is dropped rather than planned with a
+// broken id - skipped is how many that happened to, so the caller can
+// report it instead of silently emitting fewer import blocks than rows.
+func planResources(conn *SshConnection, stanzas []stanza, specs map[string]resourceSpec) (planned []plannedResource, skipped int, err error) {
+	seen := map[string]int{}
+
+	for _, st := range stanzas {
+		spec, ok := specs[st.Path]
+		if !ok {
+			// No resource in this provider owns this path; skip it rather
+			// than guessing at a schema.
+			continue
+		}
+
+		id := GetResourceId(conn, spec.Path, requiredFieldsFor(st))
+		if id == unresolvedId {
+			skipped++
+			continue
+		}
+
+		merged := mergeSelectorAndValues(st)
+		attrs := mikrotikToTerraform(spec.TerraformType, spec.Schema, merged)
+
+		name := terraformResourceName(spec.TerraformType, merged)
+		seen[name]++
+		if n := seen[name]; n > 1 {
+			name = fmt.Sprintf("%s_%d", name, n)
+		}
+
+		planned = append(planned, plannedResource{
+			TerraformType: spec.TerraformType,
+			ResourceName:  name,
+			ID:            id,
+			Attributes:    attrs,
+		})
+	}
+
+	return planned, skipped, nil
+}
+
+// requiredFieldsFor builds the `find` filters GetResourceId needs from the
+// row's `[ find ... ]` selector - the fields RouterOS itself used to
+// identify the row - rather than from every attribute the line happens to
+// assign, so a line like `set [ find default-name=telnet ] disabled=yes`
+// resolves `.id` against `default-name=telnet`, not `disabled=yes` (which
+// would match every other disabled service on the router). Falls back to
+// the assigned values only for export lines with no selector at all.
+func requiredFieldsFor(st stanza) []string {
+	fields := st.Selector
+	if len(fields) == 0 {
+		fields = st.Values
+	}
+
+	filters := make([]string, 0, len(fields))
+	for k, v := range fields {
+		filters = append(filters, fmt.Sprintf("%s=%s", k, quoteFindValue(v)))
+	}
+	sort.Strings(filters)
+	return filters
+}
+
+// quoteFindValue re-quotes a selector value parseKeyValues already stripped
+// quotes from, so a value containing whitespace round-trips back into a
+// single `find` token instead of being split into several by RouterOS.
+func quoteFindValue(v string) string {
+	if strings.ContainsAny(v, " \t") {
+		return fmt.Sprintf("%q", v)
+	}
+	return v
+}
+
+// mikrotikFieldOverrides covers the Terraform fields whose RouterOS key
+// can't be derived by a blanket underscore-to-dash substitution, e.g.
+// ResourceIpService's required `numbers` attribute is backed by the row's
+// `default-name`, not a `numbers` key RouterOS never prints.
+var mikrotikFieldOverrides = map[string]map[string]string{
+	"routeros_ip_service": {"numbers": "default-name"},
+}
+
+// mikrotikToTerraform is the reverse of MikrotikResourceDataToTerraform: it
+// walks the resource's schema rather than the RouterOS row, so computed and
+// read-only fields (no Optional/Required) are skipped the same way they are
+// never written back by the provider's own Update path.
+func mikrotikToTerraform(tfType string, resSchema map[string]*schema.Schema, values map[string]string) map[string]string {
+	attrs := make(map[string]string)
+
+	for tfName, s := range resSchema {
+		if tfName == routeros.MetaResourcePath || tfName == routeros.MetaId {
+			continue
+		}
+		if !s.Optional && !s.Required {
+			continue
+		}
+
+		mikrotikKey := mikrotikKeyFor(tfType, tfName)
+		val, ok := values[mikrotikKey]
+		if !ok {
+			continue
+		}
+
+		if s.Default != nil && fmt.Sprintf("%v", s.Default) == val {
+			continue
+		}
+
+		attrs[tfName] = val
+	}
+
+	return attrs
+}
+
+func mikrotikKeyFor(tfType, tfName string) string {
+	if overrides, ok := mikrotikFieldOverrides[tfType]; ok {
+		if key, ok := overrides[tfName]; ok {
+			return key
+		}
+	}
+	return strings.ReplaceAll(tfName, "_", "-")
+}
+
+// mergeSelectorAndValues combines a stanza's `[ find ... ]` selector with
+// its assigned values (which win on key collisions) into the single view
+// mikrotikToTerraform and terraformResourceName need: some fields that
+// identify a row, like `default-name`, are only ever present in the
+// selector and never reprinted as an assignment.
+func mergeSelectorAndValues(st stanza) map[string]string {
+	merged := make(map[string]string, len(st.Selector)+len(st.Values))
+	for k, v := range st.Selector {
+		merged[k] = v
+	}
+	for k, v := range st.Values {
+		merged[k] = v
+	}
+	return merged
+}
+
+// terraformResourceName derives a stable local name for generated HCL from
+// whatever identifying attribute the row carries (name, default-name, or
+// the RouterOS comment), falling back to the resource type itself.
+func terraformResourceName(tfType string, values map[string]string) string {
+	for _, key := range []string{"name", "default-name", "comment"} {
+		if v, ok := values[key]; ok && v != "" {
+			return sanitizeIdentifier(v)
+		}
+	}
+	return sanitizeIdentifier(tfType)
+}
+
+func sanitizeIdentifier(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	out := strings.Trim(b.String(), "_")
+	if out == "" {
+		return "resource"
+	}
+	return out
+}
+
+// renderImportBlocks emits one `import { ... }` block per planned resource,
+// in the format `terraform plan -generate-config-out` consumes.
+func renderImportBlocks(planned []plannedResource) string {
+	var b strings.Builder
+	for _, p := range planned {
+		fmt.Fprintf(&b, "import {\n  to = %s.%s\n  id = %q\n}\n\n", p.TerraformType, p.ResourceName, p.ID)
+	}
+	return b.String()
+}
+
+// renderHCL emits the generated resource blocks paired with the import
+// blocks above, so `routeros-import` can hand the user something usable
+// even before `terraform plan -generate-config-out` has run.
+func renderHCL(planned []plannedResource) string {
+	var b strings.Builder
+	for _, p := range planned {
+		fmt.Fprintf(&b, "resource %q %q {\n", p.TerraformType, p.ResourceName)
+
+		keys := make([]string, 0, len(p.Attributes))
+		for k := range p.Attributes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			fmt.Fprintf(&b, "  %s = %q\n", k, p.Attributes[k])
+		}
+
+		b.WriteString("}\n\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestRequiredFieldsForPrefersSelector(t *testing.T) {
+	st := stanza{
+		Selector: map[string]string{"default-name": "telnet"},
+		Values:   map[string]string{"disabled": "yes"},
+	}
+
+	got := requiredFieldsFor(st)
+	want := []string{"default-name=telnet"}
+	if len(got) != len(want) || got[0] != want[0] {
+		t.Fatalf("requiredFieldsFor = %v, want %v", got, want)
+	}
+}
+
+func TestRequiredFieldsForFallsBackToValues(t *testing.T) {
+	st := stanza{Values: map[string]string{"name": "telnet"}}
+
+	got := requiredFieldsFor(st)
+	if len(got) != 1 || got[0] != "name=telnet" {
+		t.Fatalf("requiredFieldsFor = %v, want [name=telnet]", got)
+	}
+}
+
+func TestRequiredFieldsForQuotesValuesContainingSpaces(t *testing.T) {
+	st := stanza{Selector: map[string]string{"comment": "Uplink to ISP"}}
+
+	got := requiredFieldsFor(st)
+	want := `comment="Uplink to ISP"`
+	if len(got) != 1 || got[0] != want {
+		t.Fatalf("requiredFieldsFor = %v, want [%s]", got, want)
+	}
+}
+
+func TestMikrotikToTerraformUsesFieldOverride(t *testing.T) {
+	resSchema := map[string]*schema.Schema{
+		"numbers": {Type: schema.TypeString, Required: true},
+		"port":    {Type: schema.TypeInt, Required: true},
+		"proto":   {Type: schema.TypeString, Computed: true},
+	}
+	values := map[string]string{"default-name": "telnet", "port": "23", "proto": "tcp"}
+
+	attrs := mikrotikToTerraform("routeros_ip_service", resSchema, values)
+
+	if attrs["numbers"] != "telnet" {
+		t.Errorf("attrs[numbers] = %q, want telnet (via default-name override)", attrs["numbers"])
+	}
+	if attrs["port"] != "23" {
+		t.Errorf("attrs[port] = %q, want 23", attrs["port"])
+	}
+	if _, ok := attrs["proto"]; ok {
+		t.Errorf("computed-only field proto must not be emitted")
+	}
+}
+
+func TestMikrotikToTerraformSkipsDefaultValue(t *testing.T) {
+	resSchema := map[string]*schema.Schema{
+		"address": {Type: schema.TypeString, Optional: true, Default: ""},
+	}
+	values := map[string]string{"address": ""}
+
+	attrs := mikrotikToTerraform("routeros_ip_service", resSchema, values)
+	if _, ok := attrs["address"]; ok {
+		t.Errorf("field matching its schema default should be omitted, got %q", attrs["address"])
+	}
+}
+
+func TestSanitizeIdentifier(t *testing.T) {
+	cases := map[string]string{
+		"www-ssl":     "www_ssl",
+		"Uplink ISP":  "Uplink_ISP",
+		"":            "resource",
+		"---":         "resource",
+	}
+	for in, want := range cases {
+		if got := sanitizeIdentifier(in); got != want {
+			t.Errorf("sanitizeIdentifier(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
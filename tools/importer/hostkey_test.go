@@ -0,0 +1,124 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func newTestKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatalf("converting to ssh.PublicKey: %v", err)
+	}
+	return sshPub
+}
+
+func TestPinnedFingerprintStoreAcceptsMatch(t *testing.T) {
+	key := newTestKey(t)
+	store := &PinnedFingerprintStore{Fingerprint: ssh.FingerprintSHA256(key)}
+
+	if err := store.Callback()("router:22", nil, key); err != nil {
+		t.Errorf("expected matching fingerprint to be accepted, got %v", err)
+	}
+}
+
+func TestPinnedFingerprintStoreRejectsMismatch(t *testing.T) {
+	key := newTestKey(t)
+	store := &PinnedFingerprintStore{Fingerprint: "SHA256:not-the-right-one"}
+
+	err := store.Callback()("router:22", nil, key)
+	var mismatch *HostKeyMismatchError
+	if err == nil {
+		t.Fatal("expected an error for a mismatched fingerprint")
+	}
+	if !asMismatch(err, &mismatch) {
+		t.Fatalf("expected a *HostKeyMismatchError, got %T: %v", err, err)
+	}
+	if mismatch.Kind != HostKeyUnknown {
+		t.Errorf("Kind = %v, want HostKeyUnknown", mismatch.Kind)
+	}
+}
+
+func TestPermissiveStoreAcceptsAnyKey(t *testing.T) {
+	store := &PermissiveStore{}
+	key := newTestKey(t)
+
+	if err := store.Callback()("router:22", nil, key); err != nil {
+		t.Errorf("expected PermissiveStore to accept any key, got %v", err)
+	}
+}
+
+func TestTofuStoreRecordsThenVerifies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	store := &TofuStore{Path: path}
+	key := newTestKey(t)
+
+	if err := store.Callback()("router:22", &net.TCPAddr{}, key); err != nil {
+		t.Fatalf("first connection should record the key, got error: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected %s to be created: %v", path, err)
+	}
+
+	if err := store.Callback()("router:22", &net.TCPAddr{}, key); err != nil {
+		t.Errorf("second connection with the same key should succeed, got: %v", err)
+	}
+}
+
+func TestTofuStoreRejectsChangedKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	store := &TofuStore{Path: path}
+
+	first := newTestKey(t)
+	if err := store.Callback()("router:22", &net.TCPAddr{}, first); err != nil {
+		t.Fatalf("first connection should record the key, got error: %v", err)
+	}
+
+	second := newTestKey(t)
+	err := store.Callback()("router:22", &net.TCPAddr{}, second)
+	var mismatch *HostKeyMismatchError
+	if err == nil {
+		t.Fatal("expected an error when the host key changes")
+	}
+	if !asMismatch(err, &mismatch) {
+		t.Fatalf("expected a *HostKeyMismatchError, got %T: %v", err, err)
+	}
+	if mismatch.Kind != HostKeyChanged {
+		t.Errorf("Kind = %v, want HostKeyChanged", mismatch.Kind)
+	}
+}
+
+func TestKnownHostsStoreRejectsUnknownHost(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	other := newTestKey(t)
+	if err := os.WriteFile(path, []byte(knownhosts.Line([]string{"other-host:22"}, other)+"\n"), 0o600); err != nil {
+		t.Fatalf("writing known_hosts: %v", err)
+	}
+
+	store := &KnownHostsStore{Path: path}
+	key := newTestKey(t)
+
+	err := store.Callback()("router:22", &net.TCPAddr{}, key)
+	var mismatch *HostKeyMismatchError
+	if err == nil {
+		t.Fatal("expected an error for a host not in known_hosts")
+	}
+	if !asMismatch(err, &mismatch) {
+		t.Fatalf("expected a *HostKeyMismatchError, got %T: %v", err, err)
+	}
+	if mismatch.Kind != HostKeyUnknown {
+		t.Errorf("Kind = %v, want HostKeyUnknown", mismatch.Kind)
+	}
+}
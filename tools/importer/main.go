@@ -0,0 +1,121 @@
+// Command routeros-import connects to a RouterOS device over SSH, reads its
+// running configuration, and generates Terraform import blocks plus
+// matching HCL for every resource type this provider knows how to manage.
+// It exists so bootstrapping state for an existing router doesn't require
+// hand-writing hundreds of resource blocks.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	host := flag.String("host", "", "RouterOS host:port to connect to over SSH")
+	username := flag.String("username", "", "SSH username")
+	password := flag.String("password", "", "SSH password")
+	identity := flag.String("identity", "", "path to an SSH private key to authenticate with")
+	useAgent := flag.Bool("use-agent", false, "authenticate via ssh-agent (SSH_AUTH_SOCK)")
+	knownHosts := flag.String("known-hosts", "", "verify the host key against this OpenSSH known_hosts file")
+	hostKeyFingerprint := flag.String("host-key-fingerprint", "", "verify the host key against this pinned SHA256 fingerprint")
+	tofuFile := flag.String("tofu-file", "", "trust-on-first-use: record and verify the host key in this file")
+	insecure := flag.Bool("insecure-accept-any-host-key", false,
+		"permissive mode: accept any host key, with a warning. For ad-hoc/lab use only - never production")
+	importOut := flag.String("import-out", "imports.tf", "path to write generated import blocks to")
+	hclOut := flag.String("hcl-out", "generated.tf", "path to write generated resource HCL to")
+	flag.Parse()
+
+	if *host == "" || *username == "" {
+		fmt.Fprintln(os.Stderr, "routeros-import: -host and -username are required")
+		os.Exit(2)
+	}
+
+	hostKeys, err := hostKeyStoreFromFlags(*knownHosts, *hostKeyFingerprint, *tofuFile, *insecure)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "routeros-import: %v\n", err)
+		os.Exit(2)
+	}
+
+	auth := AuthConfig{Password: *password, PrivateKeyPath: *identity, UseAgent: *useAgent}
+
+	if err := run(*host, *username, auth, hostKeys, *importOut, *hclOut); err != nil {
+		fmt.Fprintf(os.Stderr, "routeros-import: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// hostKeyStoreFromFlags picks exactly one trust model from the CLI flags;
+// requiring the user to choose rather than defaulting to one keeps the
+// choice deliberate, unlike the removed InsecureIgnoreHostKey default.
+// -insecure-accept-any-host-key opts into the one mode that actually
+// resembles that old default (accept-and-warn), for ad-hoc/lab use.
+func hostKeyStoreFromFlags(knownHosts, fingerprint, tofuFile string, insecure bool) (HostKeyStore, error) {
+	set := 0
+	for _, v := range []string{knownHosts, fingerprint, tofuFile} {
+		if v != "" {
+			set++
+		}
+	}
+	if insecure {
+		set++
+	}
+	switch {
+	case set == 0:
+		return nil, fmt.Errorf("one of -known-hosts, -host-key-fingerprint, -tofu-file, or " +
+			"-insecure-accept-any-host-key is required")
+	case set > 1:
+		return nil, fmt.Errorf("-known-hosts, -host-key-fingerprint, -tofu-file, and " +
+			"-insecure-accept-any-host-key are mutually exclusive")
+	case knownHosts != "":
+		return &KnownHostsStore{Path: knownHosts}, nil
+	case fingerprint != "":
+		return &PinnedFingerprintStore{Fingerprint: fingerprint}, nil
+	case tofuFile != "":
+		return &TofuStore{Path: tofuFile}, nil
+	default:
+		return &PermissiveStore{}, nil
+	}
+}
+
+func run(host, username string, auth AuthConfig, hostKeys HostKeyStore, importOut, hclOut string) error {
+	conn, err := NewSsh(host, username, auth, hostKeys)
+	if err != nil {
+		return fmt.Errorf("connecting to %s: %w", host, err)
+	}
+	defer conn.Close()
+
+	raw, err := GetMikrotikConfig(conn)
+	if err != nil {
+		return fmt.Errorf("running /export terse: %w", err)
+	}
+
+	stanzas, err := parseExport(raw)
+	if err != nil {
+		return fmt.Errorf("parsing export: %w", err)
+	}
+
+	specs, err := loadRegistry()
+	if err != nil {
+		return fmt.Errorf("loading resource registry: %w", err)
+	}
+
+	planned, skipped, err := planResources(conn, stanzas, specs)
+	if err != nil {
+		return fmt.Errorf("planning resources: %w", err)
+	}
+
+	if err := os.WriteFile(importOut, []byte(renderImportBlocks(planned)), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", importOut, err)
+	}
+
+	if err := os.WriteFile(hclOut, []byte(renderHCL(planned)), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", hclOut, err)
+	}
+
+	fmt.Printf("routeros-import: wrote %d resource(s) to %s and %s\n", len(planned), hclOut, importOut)
+	if skipped > 0 {
+		fmt.Printf("routeros-import: skipped %d resource(s) whose .id could not be resolved\n", skipped)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/davidwuest/terraform-provider-routeros/routeros"
+)
+
+// resourceSpec is the metadata the generator needs about one resource type:
+// the RouterOS path it reads from and the schema used to translate between
+// RouterOS attributes and Terraform state.
+type resourceSpec struct {
+	TerraformType string
+	Path          string
+	Schema        map[string]*schema.Schema
+}
+
+// loadRegistry walks every resource routeros.Resources() registers and
+// indexes by RouterOS path the ones this tool can generate import blocks
+// for, so a new resource becomes importable the moment it's registered in
+// that package - nothing here needs editing. A resource needs both
+// MetaResourcePath and MetaId to qualify: MetaId is what ResourceIpService
+// uses to resolve a single row's `.id` via a find selector, and aggregate
+// or singleton resources (e.g. ResourceIpServices, which owns its whole
+// RouterOS table under one Terraform ID) don't declare it, so they're
+// skipped automatically rather than needing to be special-cased.
+func loadRegistry() (map[string]resourceSpec, error) {
+	resources := routeros.Resources()
+	specs := make(map[string]resourceSpec, len(resources))
+
+	for tfType, res := range resources {
+		pathSchema, ok := res.Schema[routeros.MetaResourcePath]
+		if !ok {
+			continue
+		}
+
+		path, ok := pathSchema.Default.(string)
+		if !ok || path == "" {
+			continue
+		}
+
+		if _, ok := res.Schema[routeros.MetaId]; !ok {
+			continue
+		}
+
+		specs[path] = resourceSpec{
+			TerraformType: tfType,
+			Path:          path,
+			Schema:        res.Schema,
+		}
+	}
+
+	return specs, nil
+}
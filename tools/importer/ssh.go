@@ -3,27 +3,80 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"net"
+	"os"
+	"strings"
 
 	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
 )
 
 type SshConnection struct {
 	client *ssh.Client
 }
 
-func NewSsh(host, username, password string) (*SshConnection, error) {
-	// var hostKey ssh.PublicKey
-	// An SSH client is represented with a ClientConn.
-	//
-	// To authenticate with the remote server you must pass at least one
-	// implementation of AuthMethod via the Auth field in ClientConfig,
-	// and provide a HostKeyCallback.
+// AuthConfig selects how NewSsh authenticates. Password is tried whenever
+// it's non-empty; PrivateKeyPath and UseAgent are additive so the importer
+// can be pointed at routers where password auth has been disabled.
+type AuthConfig struct {
+	Password       string
+	PrivateKeyPath string
+	UseAgent       bool
+}
+
+func (a AuthConfig) methods() ([]ssh.AuthMethod, error) {
+	var methods []ssh.AuthMethod
+
+	if a.Password != "" {
+		methods = append(methods, ssh.Password(a.Password))
+	}
+
+	if a.PrivateKeyPath != "" {
+		key, err := os.ReadFile(a.PrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading private key %s: %w", a.PrivateKeyPath, err)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("parsing private key %s: %w", a.PrivateKeyPath, err)
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+
+	if a.UseAgent {
+		sock := os.Getenv("SSH_AUTH_SOCK")
+		if sock == "" {
+			return nil, fmt.Errorf("SSH_AUTH_SOCK is not set, cannot use ssh-agent")
+		}
+		conn, err := net.Dial("unix", sock)
+		if err != nil {
+			return nil, fmt.Errorf("connecting to ssh-agent at %s: %w", sock, err)
+		}
+		methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+	}
+
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("no authentication method configured")
+	}
+
+	return methods, nil
+}
+
+// NewSsh dials a RouterOS device over SSH. Host key verification is always
+// delegated to the supplied HostKeyStore - there is no insecure default, so
+// callers pointing this at a production router must explicitly choose a
+// trust model (known_hosts, a pinned fingerprint, TOFU, or the
+// accept-and-warn PermissiveStore).
+func NewSsh(host, username string, auth AuthConfig, hostKeys HostKeyStore) (*SshConnection, error) {
+	methods, err := auth.methods()
+	if err != nil {
+		return nil, fmt.Errorf("building auth methods: %w", err)
+	}
+
 	config := &ssh.ClientConfig{
-		User: username,
-		Auth: []ssh.AuthMethod{
-			ssh.Password(password),
-		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(), //ssh.FixedHostKey(hostKey),
+		User:            username,
+		Auth:            methods,
+		HostKeyCallback: hostKeys.Callback(),
 	}
 	client, err := ssh.Dial("tcp", host, config)
 	if err != nil {
@@ -64,27 +117,37 @@ func GetMikrotikConfig(conn *SshConnection) (string, error) {
 	return conn.Run("/export terse")
 }
 
-func GetResourceId(conn *SshConnection, path string, requiredFields []string) string {
-	var id string
-	for _, filter := range requiredFields {
-		res, err := conn.Run(fmt.Sprintf(":put [%v get [ find %v ]]", path, filter))
-		if err != nil {
-			continue
-		}
+// unresolvedId is what GetResourceId returns when it can't resolve a row's
+// `.id` - a transient SSH error, or output that didn't match reId - so
+// callers like planResources can tell "didn't resolve" apart from a real
+// id and skip the row rather than emitting a broken import block for it.
+const unresolvedId = "?"
 
-		ss := reId.FindStringSubmatch(res)
-		if len(ss) != 2 {
-			log.Error("Id not found")
-			continue
-		}
+// GetResourceId resolves a row's `.id` from a single `find` selector built
+// out of every field in requiredFields, ANDed together the way RouterOS's
+// own `find key1=val1 key2=val2` does - a multi-field selector has to be
+// evaluated as one query, since running each field as an independent find
+// and keeping the last result would silently resolve against whichever
+// field happened to run last instead of the row the full selector actually
+// identifies.
+func GetResourceId(conn *SshConnection, path string, requiredFields []string) string {
+	if len(requiredFields) == 0 {
+		log.Error("Id not found")
+		return unresolvedId
+	}
 
-		id = ss[1]
+	filter := strings.Join(requiredFields, " ")
+	res, err := conn.Run(fmt.Sprintf(":put [%v get [ find %v ]]", path, filter))
+	if err != nil {
+		log.Error("Id not found")
+		return unresolvedId
 	}
 
-	if id == "" {
+	ss := reId.FindStringSubmatch(res)
+	if len(ss) != 2 {
 		log.Error("Id not found")
-		return "?"
+		return unresolvedId
 	}
 
-	return id
+	return ss[1]
 }